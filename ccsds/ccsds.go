@@ -3,11 +3,28 @@
 package ccsds
 
 import (
+	"encoding"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 )
 
+// Errors returned while encoding a Packet.
+var (
+	// ErrEmptyData is returned when a Packet has no data, which the CCSDS
+	// data length field cannot represent.
+	ErrEmptyData = errors.New("ccsds: packet data must not be empty")
+	// ErrDataTooLarge is returned when a Packet's data exceeds the 65536
+	// bytes addressable by the 16-bit data length field.
+	ErrDataTooLarge = errors.New("ccsds: packet data exceeds 65536 bytes")
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Packet)(nil)
+	_ encoding.BinaryUnmarshaler = (*Packet)(nil)
+)
+
 // Packet represents a CCSDS packet with its header and data.
 type Packet struct {
 	// Primary header
@@ -76,6 +93,106 @@ func (d *Decoder) ReadPacket() (*Packet, error) {
 	return pkt, nil
 }
 
+// MarshalBinary encodes p as a 6-byte CCSDS primary header followed by its
+// data, implementing encoding.BinaryMarshaler. It enforces the 133.0-B-2
+// field widths (3-bit version, 11-bit APID, 14-bit sequence count) and the
+// data length rule (the field stores len(Data)-1), returning an error if any
+// of them are violated.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	if p.Version > 0x07 {
+		return nil, fmt.Errorf("ccsds: version %d exceeds 3 bits", p.Version)
+	}
+	if p.APID > 0x07FF {
+		return nil, fmt.Errorf("ccsds: APID %d exceeds 11 bits", p.APID)
+	}
+	if p.SequenceCount > 0x3FFF {
+		return nil, fmt.Errorf("ccsds: sequence count %d exceeds 14 bits", p.SequenceCount)
+	}
+	if len(p.Data) == 0 {
+		return nil, ErrEmptyData
+	}
+	if len(p.Data) > 65536 {
+		return nil, ErrDataTooLarge
+	}
+
+	b := make([]byte, 6+len(p.Data))
+
+	b[0] = p.Version<<5 | p.Type<<4
+	if p.SecondaryHdr {
+		b[0] |= 0x08
+	}
+	b[0] |= byte(p.APID>>8) & 0x07
+	b[1] = byte(p.APID)
+
+	b[2] = p.SequenceFlags<<6 | byte(p.SequenceCount>>8)&0x3F
+	b[3] = byte(p.SequenceCount)
+
+	binary.BigEndian.PutUint16(b[4:6], uint16(len(p.Data)-1))
+
+	copy(b[6:], p.Data)
+
+	return b, nil
+}
+
+// UnmarshalBinary decodes a 6-byte CCSDS primary header and its data from b
+// into p, implementing encoding.BinaryUnmarshaler.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	if len(b) < 6 {
+		return fmt.Errorf("ccsds: header requires at least 6 bytes, got %d", len(b))
+	}
+
+	p.Version = (b[0] & 0xE0) >> 5
+	p.Type = (b[0] & 0x10) >> 4
+	p.SecondaryHdr = (b[0] & 0x08) != 0
+	p.APID = uint16(b[0]&0x07)<<8 | uint16(b[1])
+	p.SequenceFlags = (b[2] & 0xC0) >> 6
+	p.SequenceCount = binary.BigEndian.Uint16([]byte{b[2] & 0x3F, b[3]})
+	p.DataLen = binary.BigEndian.Uint16(b[4:6]) + 1
+
+	if len(b) < 6+int(p.DataLen) {
+		return fmt.Errorf("ccsds: data requires %d bytes, got %d", p.DataLen, len(b)-6)
+	}
+
+	p.Data = make([]byte, p.DataLen)
+	copy(p.Data, b[6:6+int(p.DataLen)])
+
+	return nil
+}
+
+// Encoder writes CCSDS packets to an output stream.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder creates a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// WritePacket encodes pkt and writes it to the output stream, returning the
+// number of bytes written.
+func (e *Encoder) WritePacket(pkt *Packet) (int, error) {
+	b, err := pkt.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return e.w.Write(b)
+}
+
+// WriteAllPackets encodes and writes each packet in packets to the output
+// stream, stopping at the first error.
+func (e *Encoder) WriteAllPackets(packets []*Packet) (int, error) {
+	total := 0
+	for _, pkt := range packets {
+		n, err := e.WritePacket(pkt)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
 // ReadAllPackets reads all packets from the input stream until EOF.
 // It returns a slice of packets and any error encountered.
 func (d *Decoder) ReadAllPackets() ([]*Packet, error) {
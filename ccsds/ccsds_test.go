@@ -85,3 +85,62 @@ func TestDecoder_ReadAllPackets(t *testing.T) {
 	assert.Equal(t, []byte{0xDE, 0xAD, 0xBE}, packets[0].Data)
 
 }
+
+func TestEncoder_WritePacket(t *testing.T) {
+	pkt := &Packet{
+		Version:       0,
+		Type:          0,
+		SecondaryHdr:  true,
+		APID:          1,
+		SequenceFlags: 0,
+		SequenceCount: 0x3FFF,
+		Data:          []byte{0xDE, 0xAD, 0xBE},
+	}
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+
+	n, err := e.WritePacket(pkt)
+	assert.NoError(t, err)
+	assert.Equal(t, 9, n)
+
+	want, _ := hex.DecodeString("08013FFF0002DEADBE")
+	assert.Equal(t, want, buf.Bytes())
+}
+
+func TestEncoder_WritePacket_EmptyData(t *testing.T) {
+	pkt := &Packet{APID: 1}
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+
+	_, err := e.WritePacket(pkt)
+	assert.ErrorIs(t, err, ErrEmptyData)
+}
+
+func TestPacket_MarshalUnmarshalBinary_RoundTrip(t *testing.T) {
+	pkt := &Packet{
+		Version:       0,
+		Type:          1,
+		SecondaryHdr:  false,
+		APID:          0x7FF,
+		SequenceFlags: 3,
+		SequenceCount: 0x2AAA,
+		Data:          []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	b, err := pkt.MarshalBinary()
+	assert.NoError(t, err)
+
+	var got Packet
+	assert.NoError(t, got.UnmarshalBinary(b))
+
+	pkt.DataLen = uint16(len(pkt.Data))
+	assert.Equal(t, *pkt, got)
+}
+
+func TestPacket_MarshalBinary_FieldOverflow(t *testing.T) {
+	pkt := &Packet{APID: 0x0800, Data: []byte{0x01}}
+	_, err := pkt.MarshalBinary()
+	assert.Error(t, err)
+}
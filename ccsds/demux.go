@@ -0,0 +1,190 @@
+package ccsds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SequenceFlags values (CCSDS 133.0-B-2 §4.1.3.4).
+const (
+	seqFlagContinuation uint8 = 0x0
+	seqFlagFirst        uint8 = 0x1
+	seqFlagLast         uint8 = 0x2
+	seqFlagUnsegmented  uint8 = 0x3
+)
+
+// sequenceCountMod is one past the maximum value of the 14-bit
+// SequenceCount field.
+const sequenceCountMod = 1 << 14
+
+// ErrSequenceGap reports a gap in an APID's SequenceCount, detected when a
+// packet's count does not follow the previous one (modulo 16384).
+type ErrSequenceGap struct {
+	APID     uint16
+	Expected uint16
+	Got      uint16
+	Missing  uint16
+}
+
+func (e ErrSequenceGap) Error() string {
+	return fmt.Sprintf("ccsds: sequence gap on APID %d: expected %d, got %d (%d missing)", e.APID, e.Expected, e.Got, e.Missing)
+}
+
+// GapHandler is invoked when a Demuxer detects an ErrSequenceGap.
+type GapHandler func(ErrSequenceGap)
+
+// Demuxer reads packets from a Decoder and dispatches each one, by APID, to
+// a registered handler. It tracks each APID's SequenceCount to report gaps,
+// and reassembles segmented user data (SequenceFlags continuation/first/
+// last) into a single unsegmented Packet before dispatch.
+type Demuxer struct {
+	d *Decoder
+
+	handlers map[uint16]func(*Packet) error
+	def      func(*Packet) error
+
+	// GapHandler, if set, is called whenever a SequenceCount gap is
+	// detected on an APID.
+	GapHandler GapHandler
+
+	lastSeq  map[uint16]uint16
+	hasSeq   map[uint16]bool
+	segments map[uint16]*Packet
+}
+
+// NewDemuxer creates a Demuxer that reads packets from d.
+func NewDemuxer(d *Decoder) *Demuxer {
+	return &Demuxer{
+		d:        d,
+		handlers: make(map[uint16]func(*Packet) error),
+		lastSeq:  make(map[uint16]uint16),
+		hasSeq:   make(map[uint16]bool),
+		segments: make(map[uint16]*Packet),
+	}
+}
+
+// Register installs h as the handler for packets with the given APID,
+// replacing any previously registered handler.
+func (m *Demuxer) Register(apid uint16, h func(*Packet) error) {
+	m.handlers[apid] = h
+}
+
+// RegisterDefault installs h as the handler for packets whose APID has no
+// handler registered via Register.
+func (m *Demuxer) RegisterDefault(h func(*Packet) error) {
+	m.def = h
+}
+
+// Run reads and dispatches packets until ctx is canceled, the underlying
+// Decoder reaches EOF, or a handler returns an error. ctx is only checked
+// between packet reads; it cannot interrupt a blocked read.
+func (m *Demuxer) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		pkt, err := m.d.ReadPacket()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		m.checkGap(pkt)
+
+		complete, err := m.reassemble(pkt)
+		if err != nil {
+			return err
+		}
+		if complete == nil {
+			continue
+		}
+
+		h := m.handlers[complete.APID]
+		if h == nil {
+			h = m.def
+		}
+		if h == nil {
+			continue
+		}
+		if err := h(complete); err != nil {
+			return err
+		}
+	}
+}
+
+// checkGap compares pkt's SequenceCount against the last one seen for its
+// APID and invokes GapHandler if they do not form a consecutive (modulo
+// 16384) pair.
+func (m *Demuxer) checkGap(pkt *Packet) {
+	last := m.lastSeq[pkt.APID]
+	seen := m.hasSeq[pkt.APID]
+	m.lastSeq[pkt.APID] = pkt.SequenceCount
+	m.hasSeq[pkt.APID] = true
+
+	if !seen || m.GapHandler == nil {
+		return
+	}
+
+	expected := (last + 1) % sequenceCountMod
+	if pkt.SequenceCount == expected {
+		return
+	}
+
+	missing := (pkt.SequenceCount - expected + sequenceCountMod) % sequenceCountMod
+	m.GapHandler(ErrSequenceGap{
+		APID:     pkt.APID,
+		Expected: expected,
+		Got:      pkt.SequenceCount,
+		Missing:  missing,
+	})
+}
+
+// reassemble folds segmented user data into a single unsegmented Packet per
+// APID, returning it only once its last segment has arrived. Unsegmented
+// packets are returned unchanged.
+func (m *Demuxer) reassemble(pkt *Packet) (*Packet, error) {
+	switch pkt.SequenceFlags {
+	case seqFlagUnsegmented:
+		return pkt, nil
+
+	case seqFlagFirst:
+		m.segments[pkt.APID] = &Packet{
+			Version:       pkt.Version,
+			Type:          pkt.Type,
+			SecondaryHdr:  pkt.SecondaryHdr,
+			APID:          pkt.APID,
+			SequenceFlags: seqFlagUnsegmented,
+			SequenceCount: pkt.SequenceCount,
+			Data:          append([]byte(nil), pkt.Data...),
+		}
+		return nil, nil
+
+	case seqFlagContinuation:
+		seg, ok := m.segments[pkt.APID]
+		if !ok {
+			return nil, fmt.Errorf("ccsds: continuation segment for APID %d with no preceding first segment", pkt.APID)
+		}
+		seg.Data = append(seg.Data, pkt.Data...)
+		return nil, nil
+
+	default: // seqFlagLast
+		seg, ok := m.segments[pkt.APID]
+		if !ok {
+			return nil, fmt.Errorf("ccsds: last segment for APID %d with no preceding first segment", pkt.APID)
+		}
+		seg.Data = append(seg.Data, pkt.Data...)
+		delete(m.segments, pkt.APID)
+		if len(seg.Data) > 65536 {
+			return nil, fmt.Errorf("ccsds: reassembled data for APID %d exceeds 65536 bytes: %w", pkt.APID, ErrDataTooLarge)
+		}
+		seg.DataLen = uint16(len(seg.Data))
+		return seg, nil
+	}
+}
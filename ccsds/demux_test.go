@@ -0,0 +1,116 @@
+package ccsds
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeTestPacket(t *testing.T, pkt *Packet) []byte {
+	b, err := pkt.MarshalBinary()
+	assert.NoError(t, err)
+	return b
+}
+
+func TestDemuxer_DispatchByAPID(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(encodeTestPacket(t, &Packet{APID: 1, SequenceFlags: 3, Data: []byte{0x01}}))
+	stream.Write(encodeTestPacket(t, &Packet{APID: 2, SequenceFlags: 3, Data: []byte{0x02}}))
+
+	d := NewDemuxer(NewDecoder(&stream))
+
+	var gotAPID1, gotAPID2 []byte
+	d.Register(1, func(p *Packet) error {
+		gotAPID1 = p.Data
+		return nil
+	})
+	d.Register(2, func(p *Packet) error {
+		gotAPID2 = p.Data
+		return nil
+	})
+
+	assert.NoError(t, d.Run(context.Background()))
+	assert.Equal(t, []byte{0x01}, gotAPID1)
+	assert.Equal(t, []byte{0x02}, gotAPID2)
+}
+
+func TestDemuxer_RegisterDefault(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(encodeTestPacket(t, &Packet{APID: 99, SequenceFlags: 3, Data: []byte{0x09}}))
+
+	d := NewDemuxer(NewDecoder(&stream))
+
+	var got *Packet
+	d.RegisterDefault(func(p *Packet) error {
+		got = p
+		return nil
+	})
+
+	assert.NoError(t, d.Run(context.Background()))
+	assert.Equal(t, uint16(99), got.APID)
+}
+
+func TestDemuxer_SequenceGapDetection(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(encodeTestPacket(t, &Packet{APID: 1, SequenceFlags: 3, SequenceCount: 0, Data: []byte{0x01}}))
+	stream.Write(encodeTestPacket(t, &Packet{APID: 1, SequenceFlags: 3, SequenceCount: 5, Data: []byte{0x02}}))
+
+	d := NewDemuxer(NewDecoder(&stream))
+	d.RegisterDefault(func(p *Packet) error { return nil })
+
+	var gap *ErrSequenceGap
+	d.GapHandler = func(g ErrSequenceGap) {
+		gap = &g
+	}
+
+	assert.NoError(t, d.Run(context.Background()))
+	assert.Equal(t, &ErrSequenceGap{APID: 1, Expected: 1, Got: 5, Missing: 4}, gap)
+}
+
+func TestDemuxer_SequenceGapWraparound(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(encodeTestPacket(t, &Packet{APID: 1, SequenceFlags: 3, SequenceCount: 0x3FFF, Data: []byte{0x01}}))
+	stream.Write(encodeTestPacket(t, &Packet{APID: 1, SequenceFlags: 3, SequenceCount: 0, Data: []byte{0x02}}))
+
+	d := NewDemuxer(NewDecoder(&stream))
+	d.RegisterDefault(func(p *Packet) error { return nil })
+
+	var gap *ErrSequenceGap
+	d.GapHandler = func(g ErrSequenceGap) {
+		gap = &g
+	}
+
+	assert.NoError(t, d.Run(context.Background()))
+	assert.Nil(t, gap)
+}
+
+func TestDemuxer_ReassembleSegments(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(encodeTestPacket(t, &Packet{APID: 4, SequenceFlags: 1, SequenceCount: 0, Data: []byte{0x01, 0x02}}))
+	stream.Write(encodeTestPacket(t, &Packet{APID: 4, SequenceFlags: 0, SequenceCount: 1, Data: []byte{0x03, 0x04}}))
+	stream.Write(encodeTestPacket(t, &Packet{APID: 4, SequenceFlags: 2, SequenceCount: 2, Data: []byte{0x05}}))
+
+	d := NewDemuxer(NewDecoder(&stream))
+
+	var got *Packet
+	d.Register(4, func(p *Packet) error {
+		got = p
+		return nil
+	})
+
+	assert.NoError(t, d.Run(context.Background()))
+	assert.NotNil(t, got)
+	assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04, 0x05}, got.Data)
+}
+
+func TestDemuxer_ReassembleOverflowsDataLen(t *testing.T) {
+	d := NewDemuxer(NewDecoder(bytes.NewReader(nil)))
+
+	_, err := d.reassemble(&Packet{APID: 4, SequenceFlags: seqFlagFirst, Data: make([]byte, 60000)})
+	assert.NoError(t, err)
+
+	_, err = d.reassemble(&Packet{APID: 4, SequenceFlags: seqFlagLast, Data: make([]byte, 10000)})
+	assert.ErrorIs(t, err, ErrDataTooLarge)
+}
@@ -0,0 +1,295 @@
+// Package framing decodes CCSDS TM and AOS Transfer Frames (CCSDS
+// 132.0-B-3 and 732.0-B-4) from a synchronized byte stream, reassembling
+// the CCSDS Space Packets carried in their data fields via the M_PDU/B_PDU
+// first header pointer.
+//
+// Both frame decoders assume a fixed-length frame with no secondary header
+// or insert zone ahead of the data field, and, when present, a trailing
+// Reed-Solomon check symbol block followed by a 2-byte Frame Error Control
+// Field (FECF). For TM frames the first header pointer lives in the last
+// two bytes of the 6-byte primary header; for AOS frames it lives in the
+// 2-byte M_PDU header that is prepended to the data field, immediately
+// after the 6-byte primary header.
+package framing
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/Alatec/ccsds/ccsds"
+)
+
+// asm is the CCSDS default Attached Sync Marker.
+var asm = [4]byte{0x1A, 0xCF, 0xFC, 0x1D}
+
+// idleAPID is the reserved Space Packet APID used to mark fill data. Idle
+// packets are parsed but never delivered to the caller.
+const idleAPID = 0x07FF
+
+// Reserved First Header Pointer values (CCSDS 132.0-B-3 §4.1.2.7.5).
+const (
+	// fhpNoFirstHeader means the data field contains no packet start; it is
+	// entirely a continuation of the packet begun in an earlier frame.
+	fhpNoFirstHeader = 0x07FE
+	// fhpIdleFrame means the data field holds only idle/fill data.
+	fhpIdleFrame = 0x07FF
+)
+
+// RSBlock configures Reed-Solomon (255,223) descrambling of each
+// synchronized frame before its header and data field are parsed. Decode
+// receives the frame bytes following the Attached Sync Marker, including
+// any appended check symbols, and must return the corrected frame with the
+// check symbols stripped.
+type RSBlock struct {
+	Decode func(frame []byte) ([]byte, error)
+}
+
+// ErrBadFECF indicates that a frame's trailing Frame Error Control Field
+// did not match the CRC computed over the rest of the frame.
+type ErrBadFECF struct {
+	Computed uint16
+	Received uint16
+}
+
+func (e *ErrBadFECF) Error() string {
+	return fmt.Sprintf("framing: bad FECF: computed %#04x, received %#04x", e.Computed, e.Received)
+}
+
+// frameDecoder implements the synchronizer, Reed-Solomon hook, FECF check,
+// and M_PDU/B_PDU packet reassembly shared by TMFrameDecoder and
+// AOSFrameDecoder.
+type frameDecoder struct {
+	r        io.Reader
+	frameLen int // bytes per frame after the ASM, including any RS block and FECF
+	hasFECF  bool
+	// headerLen is the number of bytes preceding the data field: the
+	// primary header, plus any header that itself carries the first header
+	// pointer (TM has none extra; AOS has a 2-byte M_PDU header). The first
+	// header pointer is always the last two bytes of this region.
+	headerLen int
+
+	// ReedSolomon, if set, descrambles each frame before it is parsed.
+	ReedSolomon *RSBlock
+
+	buf    []byte // reassembled bytes of the packet currently being read
+	synced bool   // whether buf holds genuine pending packet data
+}
+
+func newFrameDecoder(r io.Reader, frameLen int, hasFECF bool, headerLen int) (*frameDecoder, error) {
+	minLen := headerLen
+	if hasFECF {
+		minLen += 2
+	}
+	if frameLen <= minLen {
+		return nil, fmt.Errorf("framing: frame length %d too small for a %d-byte header%s", frameLen, headerLen, fecfNote(hasFECF))
+	}
+	return &frameDecoder{r: r, frameLen: frameLen, hasFECF: hasFECF, headerLen: headerLen}, nil
+}
+
+func fecfNote(hasFECF bool) string {
+	if hasFECF {
+		return " and FECF"
+	}
+	return ""
+}
+
+// ReadPacket returns the next reassembled Space Packet, reading and
+// synchronizing to as many Transfer Frames as necessary. Idle packets
+// (APID 0x7FF) are skipped transparently.
+func (d *frameDecoder) ReadPacket() (*ccsds.Packet, error) {
+	for {
+		pkt, ok, err := d.popPacket()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if pkt.APID == idleAPID {
+				continue
+			}
+			return pkt, nil
+		}
+
+		if err := d.fillFromFrame(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// popPacket removes and returns one complete Space Packet from the front of
+// buf, if one is available.
+func (d *frameDecoder) popPacket() (*ccsds.Packet, bool, error) {
+	if !d.synced || len(d.buf) < 6 {
+		return nil, false, nil
+	}
+
+	dataLen := int(binary.BigEndian.Uint16(d.buf[4:6])) + 1
+	total := 6 + dataLen
+	if len(d.buf) < total {
+		return nil, false, nil
+	}
+
+	pkt := &ccsds.Packet{}
+	if err := pkt.UnmarshalBinary(d.buf[:total]); err != nil {
+		return nil, false, err
+	}
+	d.buf = d.buf[total:]
+
+	return pkt, true, nil
+}
+
+// fillFromFrame reads one Transfer Frame and merges its data field into buf
+// according to the frame's first header pointer.
+func (d *frameDecoder) fillFromFrame() error {
+	data, fhp, err := d.readFrame()
+	if err != nil {
+		return err
+	}
+
+	switch fhp {
+	case fhpIdleFrame:
+		return nil
+	case fhpNoFirstHeader:
+		if d.synced {
+			d.buf = append(d.buf, data...)
+		}
+		return nil
+	default:
+		offset := int(fhp)
+		if offset > len(data) {
+			return fmt.Errorf("framing: first header pointer %d exceeds data field length %d", offset, len(data))
+		}
+		if d.synced {
+			d.buf = append(d.buf, data[:offset]...)
+		}
+		d.buf = append(d.buf, data[offset:]...)
+		d.synced = true
+		return nil
+	}
+}
+
+// readFrame hunts for the next ASM, reads one frame, applies the
+// Reed-Solomon hook and FECF check, and returns the frame's data field
+// along with its first header pointer.
+func (d *frameDecoder) readFrame() ([]byte, uint16, error) {
+	if err := huntASM(d.r); err != nil {
+		return nil, 0, err
+	}
+
+	frame := make([]byte, d.frameLen)
+	if _, err := io.ReadFull(d.r, frame); err != nil {
+		return nil, 0, err
+	}
+
+	if d.ReedSolomon != nil {
+		corrected, err := d.ReedSolomon.Decode(frame)
+		if err != nil {
+			return nil, 0, err
+		}
+		frame = corrected
+	}
+
+	if d.hasFECF {
+		if len(frame) < 2 {
+			return nil, 0, fmt.Errorf("framing: frame too short for FECF: %d bytes", len(frame))
+		}
+		body := frame[:len(frame)-2]
+		received := binary.BigEndian.Uint16(frame[len(frame)-2:])
+		computed := crc16CCITT(body)
+		if computed != received {
+			return nil, 0, &ErrBadFECF{Computed: computed, Received: received}
+		}
+		frame = body
+	}
+
+	if len(frame) < d.headerLen {
+		return nil, 0, fmt.Errorf("framing: frame too short for header: %d bytes", len(frame))
+	}
+
+	fhp := binary.BigEndian.Uint16(frame[d.headerLen-2:d.headerLen]) & 0x07FF
+
+	return frame[d.headerLen:], fhp, nil
+}
+
+// huntASM consumes bytes from r until it has read a full Attached Sync
+// Marker, leaving the stream positioned immediately after it.
+func huntASM(r io.Reader) error {
+	var window [4]byte
+	if _, err := io.ReadFull(r, window[:]); err != nil {
+		return err
+	}
+
+	b := make([]byte, 1)
+	for window != asm {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return err
+		}
+		window[0], window[1], window[2], window[3] = window[1], window[2], window[3], b[0]
+	}
+
+	return nil
+}
+
+// crc16CCITT computes the CRC-16/CCITT-FALSE checksum (polynomial 0x1021,
+// initial value 0xFFFF, no input/output reflection, no final XOR) used as
+// the CCSDS Frame Error Control Field.
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// tmHeaderLen is the length, in bytes, of a TM Transfer Frame primary
+// header (CCSDS 132.0-B-3 §4.1.2).
+const tmHeaderLen = 6
+
+// TMFrameDecoder reassembles CCSDS Space Packets from a stream of TM
+// Transfer Frames (CCSDS 132.0-B-3).
+type TMFrameDecoder struct {
+	*frameDecoder
+}
+
+// NewTMFrameDecoder creates a TM Transfer Frame decoder that reads
+// frameLen-byte frames (the fixed frame length following the Attached Sync
+// Marker, including any Reed-Solomon check symbols and FECF) from r. Set
+// hasFECF when frames carry a trailing CRC-16 Frame Error Control Field.
+func NewTMFrameDecoder(r io.Reader, frameLen int, hasFECF bool) (*TMFrameDecoder, error) {
+	fd, err := newFrameDecoder(r, frameLen, hasFECF, tmHeaderLen)
+	if err != nil {
+		return nil, err
+	}
+	return &TMFrameDecoder{fd}, nil
+}
+
+// aosHeaderLen is the length, in bytes, of an AOS Transfer Frame primary
+// header with no insert zone (CCSDS 732.0-B-4 §4.1.2), plus the 2-byte
+// M_PDU header that carries the first header pointer (§4.1.4.2) and is
+// prepended to the data field.
+const aosHeaderLen = 6 + 2
+
+// AOSFrameDecoder reassembles CCSDS Space Packets from a stream of AOS
+// Transfer Frames (CCSDS 732.0-B-4).
+type AOSFrameDecoder struct {
+	*frameDecoder
+}
+
+// NewAOSFrameDecoder creates an AOS Transfer Frame decoder that reads
+// frameLen-byte frames (the fixed frame length following the Attached Sync
+// Marker, including any Reed-Solomon check symbols and FECF) from r. Set
+// hasFECF when frames carry a trailing CRC-16 Frame Error Control Field.
+func NewAOSFrameDecoder(r io.Reader, frameLen int, hasFECF bool) (*AOSFrameDecoder, error) {
+	fd, err := newFrameDecoder(r, frameLen, hasFECF, aosHeaderLen)
+	if err != nil {
+		return nil, err
+	}
+	return &AOSFrameDecoder{fd}, nil
+}
@@ -0,0 +1,186 @@
+package framing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/Alatec/ccsds/ccsds"
+	"github.com/stretchr/testify/assert"
+)
+
+// buildTMFrame assembles one ASM-prefixed TM Transfer Frame with a 6-byte
+// primary header carrying fhp in the low 11 bits of its last two bytes
+// (the Transfer Frame Data Field Status), the given data field, and an
+// optional trailing FECF.
+func buildTMFrame(fhp uint16, data []byte, withFECF bool) []byte {
+	header := make([]byte, 6)
+	binary.BigEndian.PutUint16(header[4:6], fhp&0x07FF)
+	return buildFrame(header, data, withFECF)
+}
+
+// buildAOSFrame assembles one ASM-prefixed AOS Transfer Frame with an
+// arbitrary 6-byte primary header followed by a 2-byte M_PDU header
+// carrying fhp in its low 11 bits, the given data field, and an optional
+// trailing FECF.
+func buildAOSFrame(fhp uint16, data []byte, withFECF bool) []byte {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint16(header[6:8], fhp&0x07FF)
+	return buildFrame(header, data, withFECF)
+}
+
+// buildFrame assembles one ASM-prefixed Transfer Frame from a caller-built
+// header (primary header, plus any header carrying the first header
+// pointer), the given data field, and an optional trailing FECF.
+func buildFrame(header, data []byte, withFECF bool) []byte {
+	var frame bytes.Buffer
+	frame.Write(asm[:])
+	frame.Write(header)
+	frame.Write(data)
+
+	if withFECF {
+		crc := crc16CCITT(frame.Bytes()[4:])
+		var trailer [2]byte
+		binary.BigEndian.PutUint16(trailer[:], crc)
+		frame.Write(trailer[:])
+	}
+
+	return frame.Bytes()
+}
+
+func encodePacket(t *testing.T, pkt *ccsds.Packet) []byte {
+	b, err := pkt.MarshalBinary()
+	assert.NoError(t, err)
+	return b
+}
+
+func TestTMFrameDecoder_SingleFramePacket(t *testing.T) {
+	pkt := encodePacket(t, &ccsds.Packet{APID: 5, SequenceFlags: 3, Data: []byte{0xDE, 0xAD, 0xBE, 0xEF}})
+	assert.Len(t, pkt, 10)
+
+	frame := buildTMFrame(0, pkt, false)
+
+	dec, err := NewTMFrameDecoder(bytes.NewReader(frame), len(frame)-4, false)
+	assert.NoError(t, err)
+
+	got, err := dec.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(5), got.APID)
+	assert.Equal(t, []byte{0xDE, 0xAD, 0xBE, 0xEF}, got.Data)
+}
+
+func TestTMFrameDecoder_PacketSpansTwoFrames(t *testing.T) {
+	pkt := encodePacket(t, &ccsds.Packet{APID: 9, Data: []byte{1, 2, 3, 4, 5, 6, 7, 8}})
+	assert.Len(t, pkt, 14)
+
+	const dataLen = 7
+	frame1 := buildTMFrame(0, pkt[:dataLen], false)
+	frame2 := buildTMFrame(fhpNoFirstHeader, pkt[dataLen:], false)
+
+	var stream bytes.Buffer
+	stream.Write(frame1)
+	stream.Write(frame2)
+
+	dec, err := NewTMFrameDecoder(&stream, 6+dataLen, false)
+	assert.NoError(t, err)
+
+	got, err := dec.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(9), got.APID)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, got.Data)
+}
+
+func TestTMFrameDecoder_SkipsIdlePacket(t *testing.T) {
+	idle := encodePacket(t, &ccsds.Packet{APID: idleAPID, Data: []byte{0x00}})
+	real := encodePacket(t, &ccsds.Packet{APID: 3, Data: []byte{0xAA}})
+
+	data := append(append([]byte{}, idle...), real...)
+	frame := buildTMFrame(0, data, false)
+
+	dec, err := NewTMFrameDecoder(bytes.NewReader(frame), len(frame)-4, false)
+	assert.NoError(t, err)
+
+	got, err := dec.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(3), got.APID)
+	assert.Equal(t, []byte{0xAA}, got.Data)
+}
+
+func TestTMFrameDecoder_IdleFrameSkipped(t *testing.T) {
+	idleFrame := buildTMFrame(fhpIdleFrame, make([]byte, 7), false)
+
+	pkt := encodePacket(t, &ccsds.Packet{APID: 3, Data: []byte{0xAA}})
+	realFrame := buildTMFrame(0, pkt, false)
+
+	var stream bytes.Buffer
+	stream.Write(idleFrame)
+	stream.Write(realFrame)
+
+	dec, err := NewTMFrameDecoder(&stream, 13, false)
+	assert.NoError(t, err)
+
+	got, err := dec.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(3), got.APID)
+}
+
+func TestTMFrameDecoder_FECF(t *testing.T) {
+	pkt := encodePacket(t, &ccsds.Packet{APID: 3, Data: []byte{0xAA}})
+	frame := buildTMFrame(0, pkt, true)
+
+	dec, err := NewTMFrameDecoder(bytes.NewReader(frame), len(frame)-4, true)
+	assert.NoError(t, err)
+
+	got, err := dec.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(3), got.APID)
+
+	corrupt := append([]byte(nil), frame...)
+	corrupt[len(corrupt)-1] ^= 0xFF
+
+	dec2, err := NewTMFrameDecoder(bytes.NewReader(corrupt), len(corrupt)-4, true)
+	assert.NoError(t, err)
+
+	_, err = dec2.ReadPacket()
+	var badFECF *ErrBadFECF
+	assert.ErrorAs(t, err, &badFECF)
+}
+
+func TestNewTMFrameDecoder_FrameTooSmall(t *testing.T) {
+	_, err := NewTMFrameDecoder(bytes.NewReader(nil), 4, false)
+	assert.Error(t, err)
+}
+
+func TestAOSFrameDecoder_SingleFramePacket(t *testing.T) {
+	pkt := encodePacket(t, &ccsds.Packet{APID: 7, Data: []byte{0x01, 0x02}})
+	frame := buildAOSFrame(0, pkt, false)
+
+	dec, err := NewAOSFrameDecoder(bytes.NewReader(frame), len(frame)-4, false)
+	assert.NoError(t, err)
+
+	got, err := dec.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(7), got.APID)
+	assert.Equal(t, []byte{0x01, 0x02}, got.Data)
+}
+
+func TestAOSFrameDecoder_PacketSpansTwoFrames(t *testing.T) {
+	pkt := encodePacket(t, &ccsds.Packet{APID: 11, Data: []byte{1, 2, 3, 4, 5, 6, 7, 8}})
+	assert.Len(t, pkt, 14)
+
+	const dataLen = 7
+	frame1 := buildAOSFrame(0, pkt[:dataLen], false)
+	frame2 := buildAOSFrame(fhpNoFirstHeader, pkt[dataLen:], false)
+
+	var stream bytes.Buffer
+	stream.Write(frame1)
+	stream.Write(frame2)
+
+	dec, err := NewAOSFrameDecoder(&stream, 8+dataLen, false)
+	assert.NoError(t, err)
+
+	got, err := dec.ReadPacket()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(11), got.APID)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8}, got.Data)
+}
@@ -0,0 +1,239 @@
+// Package pus decodes ECSS Packet Utilisation Standard (ECSS-E-ST-70-41C)
+// telemetry and telecommand secondary headers carried in a CCSDS Space
+// Packet's Data field.
+package pus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Alatec/ccsds/ccsds"
+	"github.com/Alatec/ccsds/ccsds/timecode"
+)
+
+// ErrNoSecondaryHeader is returned when a packet's SecondaryHdr flag is not
+// set.
+var ErrNoSecondaryHeader = errors.New("pus: packet has no secondary header")
+
+// ErrBadCRC indicates that a packet's trailing Packet Error Control field did
+// not match the CRC computed over the preceding data.
+type ErrBadCRC struct {
+	Computed uint16 // CRC-16/CCITT computed over the data
+	Received uint16 // Packet Error Control field from the packet
+}
+
+func (e *ErrBadCRC) Error() string {
+	return fmt.Sprintf("pus: CRC mismatch: computed %#04x, received %#04x", e.Computed, e.Received)
+}
+
+// PusTmSecondaryHeader is the ECSS-E-ST-70-41C PUS-A/PUS-C telemetry
+// secondary header.
+type PusTmSecondaryHeader struct {
+	Version           uint8  // PUS version number (4 bits)
+	ServiceType       uint8  // PUS service type
+	ServiceSubtype    uint8  // PUS service subtype
+	MessageSubcounter uint16 // message type counter / subcounter
+	DestinationID     uint16
+	Time              []byte // raw absolute time field, Parser.TimeLen bytes
+	// Timestamp is Time decoded into a time.Time. It is the zero time.Time
+	// unless Parser.TimeFormat is configured.
+	Timestamp time.Time
+}
+
+// TimeKind selects the CCSDS time code format used to decode a TM secondary
+// header's Time field.
+type TimeKind int
+
+const (
+	// TimeKindCUC decodes Time as a CCSDS Unsegmented (CUC) time code.
+	TimeKindCUC TimeKind = iota
+	// TimeKindCDS decodes Time as a CCSDS Day Segmented (CDS) time code.
+	TimeKindCDS
+)
+
+// TimeFormat configures how a TM secondary header's Time field, which
+// carries no CCSDS P-field of its own, is decoded into a time.Time.
+type TimeFormat struct {
+	Kind TimeKind
+
+	// CoarseBytes and FineBytes give the CUC T-field split; they are
+	// ignored unless Kind is TimeKindCUC. CoarseBytes+FineBytes must equal
+	// the Parser's TimeLen.
+	CoarseBytes int
+	FineBytes   int
+
+	// DayBytes (2 for 16-bit days, 3 for 24-bit days) and SubFormat give the
+	// CDS T-field split; they are ignored unless Kind is TimeKindCDS.
+	// DayBytes+4+the submillisecond segment width must equal TimeLen.
+	DayBytes  int
+	SubFormat timecode.SubFormat
+
+	// Epoch is the time code origin. The zero value uses timecode.Epoch1958.
+	Epoch time.Time
+}
+
+// PusTcSecondaryHeader is the ECSS-E-ST-70-41C PUS-A/PUS-C telecommand
+// secondary header.
+type PusTcSecondaryHeader struct {
+	Version        uint8 // PUS version number (4 bits)
+	AckFlags       uint8 // acknowledgement flags (4 bits)
+	ServiceType    uint8 // PUS service type
+	ServiceSubtype uint8 // PUS service subtype
+	SourceID       uint16
+}
+
+// Parser decodes PUS secondary headers according to a mission's time-stamp
+// length and CRC settings.
+type Parser struct {
+	// TimeLen is the length, in bytes, of the absolute time field in a TM
+	// secondary header. Valid values are 0, 6, 8, and 10.
+	TimeLen int
+	// CheckCRC enables verification of the trailing 2-byte Packet Error
+	// Control field in Data against a CRC-16/CCITT computed over the rest
+	// of the data.
+	CheckCRC bool
+	// TimeFormat, if non-nil, decodes a TM secondary header's Time bytes
+	// into PusTmSecondaryHeader.Timestamp.
+	TimeFormat *TimeFormat
+}
+
+// NewParser creates a Parser for the given time-stamp length and CRC
+// setting. It returns an error if timeLen is not one of 0, 6, 8, or 10.
+func NewParser(timeLen int, checkCRC bool) (*Parser, error) {
+	switch timeLen {
+	case 0, 6, 8, 10:
+	default:
+		return nil, fmt.Errorf("pus: unsupported time-stamp length %d", timeLen)
+	}
+	return &Parser{TimeLen: timeLen, CheckCRC: checkCRC}, nil
+}
+
+// ParseTM decodes the PUS telemetry secondary header and payload from pkt.
+func (p *Parser) ParseTM(pkt *ccsds.Packet) (*PusTmSecondaryHeader, []byte, error) {
+	if !pkt.SecondaryHdr {
+		return nil, nil, ErrNoSecondaryHeader
+	}
+	if pkt.Type != 0 {
+		return nil, nil, errors.New("pus: packet is not telemetry")
+	}
+
+	data, err := p.stripCRC(pkt.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hdrLen := 7 + p.TimeLen
+	if len(data) < hdrLen {
+		return nil, nil, fmt.Errorf("pus: TM secondary header requires %d bytes, got %d", hdrLen, len(data))
+	}
+
+	hdr := &PusTmSecondaryHeader{
+		Version:           (data[0] & 0xF0) >> 4,
+		ServiceType:       data[1],
+		ServiceSubtype:    data[2],
+		MessageSubcounter: binary.BigEndian.Uint16(data[3:5]),
+		DestinationID:     binary.BigEndian.Uint16(data[5:7]),
+	}
+	if p.TimeLen > 0 {
+		hdr.Time = append([]byte(nil), data[7:hdrLen]...)
+
+		if p.TimeFormat != nil {
+			ts, err := p.TimeFormat.decode(hdr.Time)
+			if err != nil {
+				return nil, nil, err
+			}
+			hdr.Timestamp = ts
+		}
+	}
+
+	return hdr, data[hdrLen:], nil
+}
+
+// decode parses raw, the PusTmSecondaryHeader.Time bytes, into a time.Time
+// according to f.
+func (f *TimeFormat) decode(raw []byte) (time.Time, error) {
+	epoch := f.Epoch
+	if epoch.IsZero() {
+		epoch = timecode.Epoch1958
+	}
+
+	switch f.Kind {
+	case TimeKindCUC:
+		return timecode.DecodeCUCRaw(raw, f.CoarseBytes, f.FineBytes, epoch)
+	case TimeKindCDS:
+		return timecode.DecodeCDSRaw(raw, f.DayBytes, f.SubFormat, epoch)
+	default:
+		return time.Time{}, fmt.Errorf("pus: unsupported time format kind %d", f.Kind)
+	}
+}
+
+// ParseTC decodes the PUS telecommand secondary header and payload from pkt.
+func (p *Parser) ParseTC(pkt *ccsds.Packet) (*PusTcSecondaryHeader, []byte, error) {
+	if !pkt.SecondaryHdr {
+		return nil, nil, ErrNoSecondaryHeader
+	}
+	if pkt.Type != 1 {
+		return nil, nil, errors.New("pus: packet is not a telecommand")
+	}
+
+	data, err := p.stripCRC(pkt.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	const hdrLen = 5
+	if len(data) < hdrLen {
+		return nil, nil, fmt.Errorf("pus: TC secondary header requires %d bytes, got %d", hdrLen, len(data))
+	}
+
+	hdr := &PusTcSecondaryHeader{
+		Version:        (data[0] & 0xF0) >> 4,
+		AckFlags:       data[0] & 0x0F,
+		ServiceType:    data[1],
+		ServiceSubtype: data[2],
+		SourceID:       binary.BigEndian.Uint16(data[3:5]),
+	}
+
+	return hdr, data[hdrLen:], nil
+}
+
+// stripCRC verifies and removes the trailing 2-byte Packet Error Control
+// field from data when CheckCRC is enabled. It returns data unchanged
+// otherwise.
+func (p *Parser) stripCRC(data []byte) ([]byte, error) {
+	if !p.CheckCRC {
+		return data, nil
+	}
+	if len(data) < 2 {
+		return nil, fmt.Errorf("pus: data too short for Packet Error Control field: %d bytes", len(data))
+	}
+
+	payload := data[:len(data)-2]
+	received := binary.BigEndian.Uint16(data[len(data)-2:])
+	computed := crc16CCITT(payload)
+	if computed != received {
+		return nil, &ErrBadCRC{Computed: computed, Received: received}
+	}
+
+	return payload, nil
+}
+
+// crc16CCITT computes the CRC-16/CCITT-FALSE checksum (polynomial 0x1021,
+// initial value 0xFFFF, no input/output reflection, no final XOR) used as
+// the CCSDS Packet Error Control field.
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
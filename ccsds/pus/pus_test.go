@@ -0,0 +1,113 @@
+package pus
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/Alatec/ccsds/ccsds"
+	"github.com/Alatec/ccsds/ccsds/timecode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseTM(t *testing.T) {
+	// Secondary header: Version 1, ServiceType 3, ServiceSubtype 25,
+	// MessageSubcounter 0x0001, DestinationID 0x0002, Time 0xAABBCCDDEEFF
+	// Payload: 0xDE 0xAD
+	data, err := hex.DecodeString("10031900010002AABBCCDDEEFFDEAD")
+	assert.NoError(t, err)
+
+	p, err := NewParser(6, false)
+	assert.NoError(t, err)
+
+	pkt := &ccsds.Packet{Type: 0, SecondaryHdr: true, Data: data}
+
+	hdr, payload, err := p.ParseTM(pkt)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(1), hdr.Version)
+	assert.Equal(t, uint8(3), hdr.ServiceType)
+	assert.Equal(t, uint8(25), hdr.ServiceSubtype)
+	assert.Equal(t, uint16(1), hdr.MessageSubcounter)
+	assert.Equal(t, uint16(2), hdr.DestinationID)
+	assert.Equal(t, []byte{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}, hdr.Time)
+	assert.Equal(t, []byte{0xDE, 0xAD}, payload)
+}
+
+func TestParser_ParseTM_Timestamp(t *testing.T) {
+	data, err := hex.DecodeString("10031900010002AABBCCDDEEFFDEAD")
+	assert.NoError(t, err)
+
+	p, err := NewParser(6, false)
+	assert.NoError(t, err)
+	p.TimeFormat = &TimeFormat{Kind: TimeKindCUC, CoarseBytes: 4, FineBytes: 2}
+
+	pkt := &ccsds.Packet{Type: 0, SecondaryHdr: true, Data: data}
+
+	hdr, _, err := p.ParseTM(pkt)
+	assert.NoError(t, err)
+
+	want, err := timecode.DecodeCUCRaw([]byte{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}, 4, 2, timecode.Epoch1958)
+	assert.NoError(t, err)
+	assert.True(t, want.Equal(hdr.Timestamp))
+}
+
+func TestParser_ParseTM_NoSecondaryHeader(t *testing.T) {
+	p, err := NewParser(0, false)
+	assert.NoError(t, err)
+
+	pkt := &ccsds.Packet{Type: 0, SecondaryHdr: false, Data: []byte{0x00}}
+
+	_, _, err = p.ParseTM(pkt)
+	assert.ErrorIs(t, err, ErrNoSecondaryHeader)
+}
+
+func TestParser_ParseTC(t *testing.T) {
+	// Secondary header: Version 2, AckFlags 0xB, ServiceType 17, ServiceSubtype 1,
+	// SourceID 0x00FF
+	// Payload: 0x01 0x02 0x03
+	data, err := hex.DecodeString("2B110100FF010203")
+	assert.NoError(t, err)
+
+	p, err := NewParser(0, false)
+	assert.NoError(t, err)
+
+	pkt := &ccsds.Packet{Type: 1, SecondaryHdr: true, Data: data}
+
+	hdr, payload, err := p.ParseTC(pkt)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(2), hdr.Version)
+	assert.Equal(t, uint8(0xB), hdr.AckFlags)
+	assert.Equal(t, uint8(17), hdr.ServiceType)
+	assert.Equal(t, uint8(1), hdr.ServiceSubtype)
+	assert.Equal(t, uint16(0x00FF), hdr.SourceID)
+	assert.Equal(t, []byte{0x01, 0x02, 0x03}, payload)
+}
+
+func TestParser_ParseTC_CRC(t *testing.T) {
+	// Same header/payload as above, followed by a valid CRC-16/CCITT trailer.
+	data, err := hex.DecodeString("2B110100FF010203")
+	assert.NoError(t, err)
+	data = append(data, byte(crc16CCITT(data)>>8), byte(crc16CCITT(data)))
+
+	p, err := NewParser(0, true)
+	assert.NoError(t, err)
+
+	pkt := &ccsds.Packet{Type: 1, SecondaryHdr: true, Data: data}
+
+	_, payload, err := p.ParseTC(pkt)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02, 0x03}, payload)
+
+	// Corrupt the CRC and confirm ErrBadCRC is returned.
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-1] ^= 0xFF
+	pkt.Data = corrupt
+
+	_, _, err = p.ParseTC(pkt)
+	var badCRC *ErrBadCRC
+	assert.ErrorAs(t, err, &badCRC)
+}
+
+func TestNewParser_InvalidTimeLen(t *testing.T) {
+	_, err := NewParser(5, false)
+	assert.Error(t, err)
+}
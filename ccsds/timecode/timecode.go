@@ -0,0 +1,302 @@
+// Package timecode decodes and encodes the CCSDS 301.0-B-4 Unsegmented
+// (CUC) and Day Segmented (CDS) time code formats.
+package timecode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Epoch1958 is the CCSDS default time code epoch: 1958-01-01T00:00:00 TAI.
+var Epoch1958 = time.Date(1958, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SubFormat selects the width of a CDS time code's submillisecond segment.
+type SubFormat int
+
+const (
+	// SubFormatNone means the time code carries no submillisecond segment.
+	SubFormatNone SubFormat = iota
+	// SubFormatMicros16 is a 16-bit microseconds-of-millisecond segment.
+	SubFormatMicros16
+	// SubFormatPicos32 is a 32-bit picoseconds-of-millisecond segment.
+	SubFormatPicos32
+)
+
+func resolveEpoch(epoch []time.Time) time.Time {
+	if len(epoch) > 0 {
+		return epoch[0]
+	}
+	return Epoch1958
+}
+
+// ParseCUC decodes a CCSDS Unsegmented (CUC) time code from b, starting with
+// its P-field, and returns the decoded time and the number of bytes
+// consumed. epoch defaults to Epoch1958 when omitted.
+func ParseCUC(b []byte, epoch ...time.Time) (time.Time, int, error) {
+	if len(b) < 1 {
+		return time.Time{}, 0, fmt.Errorf("timecode: CUC P-field requires at least 1 byte")
+	}
+
+	pField := b[0]
+	consumed := 1
+	coarseBytes := int((pField>>2)&0x03) + 1
+	fineBytes := int(pField & 0x03)
+
+	if pField&0x80 != 0 {
+		if len(b) < 2 {
+			return time.Time{}, 0, fmt.Errorf("timecode: extended CUC P-field requires a second byte")
+		}
+		ext := b[1]
+		coarseBytes += int((ext >> 5) & 0x03)
+		fineBytes += int((ext >> 2) & 0x07)
+		consumed = 2
+	}
+
+	tLen := coarseBytes + fineBytes
+	if len(b) < consumed+tLen {
+		return time.Time{}, 0, fmt.Errorf("timecode: CUC T-field requires %d bytes, got %d", tLen, len(b)-consumed)
+	}
+
+	t, err := DecodeCUCRaw(b[consumed:consumed+tLen], coarseBytes, fineBytes, resolveEpoch(epoch))
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return t, consumed + tLen, nil
+}
+
+// EncodeCUC encodes t as a CCSDS Unsegmented (CUC) time code, including its
+// P-field, using coarseBytes (1..4) and fineBytes (0..3) octets for the
+// T-field. epoch defaults to Epoch1958 when omitted.
+func EncodeCUC(t time.Time, coarseBytes, fineBytes int, epoch ...time.Time) ([]byte, error) {
+	if coarseBytes < 1 || coarseBytes > 4 {
+		return nil, fmt.Errorf("timecode: CUC coarse byte count must be 1..4, got %d", coarseBytes)
+	}
+	if fineBytes < 0 || fineBytes > 3 {
+		return nil, fmt.Errorf("timecode: CUC fine byte count must be 0..3, got %d", fineBytes)
+	}
+
+	e := resolveEpoch(epoch)
+	raw, err := EncodeCUCRaw(t, coarseBytes, fineBytes, e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Time code identification: 001 for the 1958 epoch, 010 for any other.
+	pField := byte(0x20)
+	if e.Equal(Epoch1958) {
+		pField = 0x10
+	}
+	pField |= byte(coarseBytes-1)<<2 | byte(fineBytes)
+
+	return append([]byte{pField}, raw...), nil
+}
+
+// DecodeCUCRaw decodes a CUC T-field of exactly coarseBytes+fineBytes bytes,
+// with no P-field, relative to epoch.
+func DecodeCUCRaw(b []byte, coarseBytes, fineBytes int, epoch time.Time) (time.Time, error) {
+	tLen := coarseBytes + fineBytes
+	if len(b) < tLen {
+		return time.Time{}, fmt.Errorf("timecode: CUC T-field requires %d bytes, got %d", tLen, len(b))
+	}
+
+	var coarse uint64
+	for _, v := range b[:coarseBytes] {
+		coarse = coarse<<8 | uint64(v)
+	}
+
+	var fraction float64
+	if fineBytes > 0 {
+		var fine uint64
+		for _, v := range b[coarseBytes:tLen] {
+			fine = fine<<8 | uint64(v)
+		}
+		fraction = float64(fine) / math.Pow(256, float64(fineBytes))
+	}
+
+	t := epoch.Add(time.Duration(coarse) * time.Second)
+	t = t.Add(time.Duration(fraction * float64(time.Second)))
+
+	return t, nil
+}
+
+// EncodeCUCRaw is the inverse of DecodeCUCRaw: it encodes a CUC T-field of
+// exactly coarseBytes+fineBytes bytes, with no P-field, relative to epoch.
+func EncodeCUCRaw(t time.Time, coarseBytes, fineBytes int, epoch time.Time) ([]byte, error) {
+	secs := t.Sub(epoch).Seconds()
+	if secs < 0 {
+		return nil, fmt.Errorf("timecode: time %s precedes epoch %s", t, epoch)
+	}
+
+	coarse := uint64(secs)
+	fraction := secs - float64(coarse)
+
+	buf := make([]byte, 8)
+	out := make([]byte, coarseBytes+fineBytes)
+
+	binary.BigEndian.PutUint64(buf, coarse)
+	copy(out[:coarseBytes], buf[8-coarseBytes:])
+
+	if fineBytes > 0 {
+		fine := uint64(fraction * math.Pow(256, float64(fineBytes)))
+		binary.BigEndian.PutUint64(buf, fine)
+		copy(out[coarseBytes:], buf[8-fineBytes:])
+	}
+
+	return out, nil
+}
+
+// ParseCDS decodes a CCSDS Day Segmented (CDS) time code from b, starting
+// with its P-field, and returns the decoded time and the number of bytes
+// consumed. epoch defaults to Epoch1958 when omitted.
+func ParseCDS(b []byte, epoch ...time.Time) (time.Time, int, error) {
+	if len(b) < 1 {
+		return time.Time{}, 0, fmt.Errorf("timecode: CDS P-field requires at least 1 byte")
+	}
+
+	pField := b[0]
+	consumed := 1
+
+	dayBytes := 2
+	if pField&0x04 != 0 {
+		dayBytes = 3
+	}
+
+	sub := SubFormat(pField & 0x03)
+	subBytes, err := sub.byteLen()
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	tLen := dayBytes + 4 + subBytes
+	if len(b) < consumed+tLen {
+		return time.Time{}, 0, fmt.Errorf("timecode: CDS T-field requires %d bytes, got %d", tLen, len(b)-consumed)
+	}
+
+	t, err := DecodeCDSRaw(b[consumed:consumed+tLen], dayBytes, sub, resolveEpoch(epoch))
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return t, consumed + tLen, nil
+}
+
+// EncodeCDS encodes t as a CCSDS Day Segmented (CDS) time code, including
+// its P-field, using a 24-bit day segment when extendedDays is set (16-bit
+// otherwise) and sub as the submillisecond segment format. epoch defaults
+// to Epoch1958 when omitted.
+func EncodeCDS(t time.Time, extendedDays bool, sub SubFormat, epoch ...time.Time) ([]byte, error) {
+	dayBytes := 2
+	if extendedDays {
+		dayBytes = 3
+	}
+
+	e := resolveEpoch(epoch)
+	raw, err := EncodeCDSRaw(t, dayBytes, sub, e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Time code identification 100, then the epoch identification bit (set
+	// for any epoch other than 1958).
+	pField := byte(0x40)
+	if !e.Equal(Epoch1958) {
+		pField |= 0x08
+	}
+	if extendedDays {
+		pField |= 0x04
+	}
+	pField |= byte(sub)
+
+	return append([]byte{pField}, raw...), nil
+}
+
+// DecodeCDSRaw decodes a CDS T-field of exactly dayBytes+4+sub.byteLen()
+// bytes, with no P-field, relative to epoch.
+func DecodeCDSRaw(b []byte, dayBytes int, sub SubFormat, epoch time.Time) (time.Time, error) {
+	subBytes, err := sub.byteLen()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	tLen := dayBytes + 4 + subBytes
+	if len(b) < tLen {
+		return time.Time{}, fmt.Errorf("timecode: CDS T-field requires %d bytes, got %d", tLen, len(b))
+	}
+
+	var days uint32
+	for _, v := range b[:dayBytes] {
+		days = days<<8 | uint32(v)
+	}
+
+	msOfDay := binary.BigEndian.Uint32(b[dayBytes : dayBytes+4])
+
+	var subNanos time.Duration
+	switch sub {
+	case SubFormatMicros16:
+		subNanos = time.Duration(binary.BigEndian.Uint16(b[dayBytes+4:dayBytes+6])) * time.Microsecond
+	case SubFormatPicos32:
+		subNanos = time.Duration(binary.BigEndian.Uint32(b[dayBytes+4:dayBytes+8]) / 1000)
+	}
+
+	t := epoch.Add(time.Duration(days) * 24 * time.Hour)
+	t = t.Add(time.Duration(msOfDay) * time.Millisecond)
+	t = t.Add(subNanos)
+
+	return t, nil
+}
+
+// EncodeCDSRaw is the inverse of DecodeCDSRaw: it encodes a CDS T-field of
+// exactly dayBytes+4+sub.byteLen() bytes, with no P-field, relative to
+// epoch.
+func EncodeCDSRaw(t time.Time, dayBytes int, sub SubFormat, epoch time.Time) ([]byte, error) {
+	subBytes, err := sub.byteLen()
+	if err != nil {
+		return nil, err
+	}
+	if dayBytes != 2 && dayBytes != 3 {
+		return nil, fmt.Errorf("timecode: CDS day byte count must be 2 or 3, got %d", dayBytes)
+	}
+
+	d := t.Sub(epoch)
+	if d < 0 {
+		return nil, fmt.Errorf("timecode: time %s precedes epoch %s", t, epoch)
+	}
+
+	days := uint32(d / (24 * time.Hour))
+	remainder := d % (24 * time.Hour)
+	msOfDay := uint32(remainder / time.Millisecond)
+	subRemainder := remainder % time.Millisecond
+
+	out := make([]byte, dayBytes+4+subBytes)
+
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, days)
+	copy(out[:dayBytes], buf[4-dayBytes:])
+
+	binary.BigEndian.PutUint32(out[dayBytes:dayBytes+4], msOfDay)
+
+	switch sub {
+	case SubFormatMicros16:
+		binary.BigEndian.PutUint16(out[dayBytes+4:dayBytes+6], uint16(subRemainder/time.Microsecond))
+	case SubFormatPicos32:
+		binary.BigEndian.PutUint32(out[dayBytes+4:dayBytes+8], uint32(subRemainder.Nanoseconds()*1000))
+	}
+
+	return out, nil
+}
+
+func (s SubFormat) byteLen() (int, error) {
+	switch s {
+	case SubFormatNone:
+		return 0, nil
+	case SubFormatMicros16:
+		return 2, nil
+	case SubFormatPicos32:
+		return 4, nil
+	default:
+		return 0, fmt.Errorf("timecode: unsupported CDS submillisecond format %d", s)
+	}
+}
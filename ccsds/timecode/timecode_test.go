@@ -0,0 +1,69 @@
+package timecode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCUC_RoundTrip(t *testing.T) {
+	want := Epoch1958.Add(1234567*time.Second + 500*time.Millisecond)
+
+	b, err := EncodeCUC(want, 4, 2)
+	assert.NoError(t, err)
+	assert.Len(t, b, 7) // 1 P-field + 4 coarse + 2 fine
+
+	got, consumed, err := ParseCUC(b)
+	assert.NoError(t, err)
+	assert.Equal(t, len(b), consumed)
+	assert.WithinDuration(t, want, got, time.Millisecond)
+}
+
+func TestCUC_CustomEpoch(t *testing.T) {
+	epoch := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	want := epoch.Add(10 * time.Second)
+
+	b, err := EncodeCUC(want, 4, 0, epoch)
+	assert.NoError(t, err)
+
+	got, _, err := ParseCUC(b, epoch)
+	assert.NoError(t, err)
+	assert.True(t, want.Equal(got))
+}
+
+func TestCDS_RoundTrip_Micros(t *testing.T) {
+	want := Epoch1958.Add(3*24*time.Hour + 5*time.Hour + 250*time.Microsecond)
+
+	b, err := EncodeCDS(want, false, SubFormatMicros16)
+	assert.NoError(t, err)
+	assert.Len(t, b, 1+2+4+2)
+
+	got, consumed, err := ParseCDS(b)
+	assert.NoError(t, err)
+	assert.Equal(t, len(b), consumed)
+	assert.WithinDuration(t, want, got, time.Microsecond)
+}
+
+func TestCDS_RoundTrip_Picos_ExtendedDays(t *testing.T) {
+	want := Epoch1958.Add(100000*24*time.Hour + 42*time.Millisecond)
+
+	b, err := EncodeCDS(want, true, SubFormatPicos32)
+	assert.NoError(t, err)
+	assert.Len(t, b, 1+3+4+4)
+
+	got, consumed, err := ParseCDS(b)
+	assert.NoError(t, err)
+	assert.Equal(t, len(b), consumed)
+	assert.WithinDuration(t, want, got, time.Microsecond)
+}
+
+func TestParseCUC_ShortBuffer(t *testing.T) {
+	_, _, err := ParseCUC(nil)
+	assert.Error(t, err)
+}
+
+func TestParseCDS_ShortBuffer(t *testing.T) {
+	_, _, err := ParseCDS([]byte{0x00})
+	assert.Error(t, err)
+}
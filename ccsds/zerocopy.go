@@ -0,0 +1,117 @@
+package ccsds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ParsePacket decodes a single packet from the front of b without copying:
+// the returned Packet's Data aliases b's backing array. The caller must not
+// mutate b, nor let it be reused or garbage collected, while the Packet is
+// still in use. It returns the packet and the number of bytes consumed from
+// b.
+func ParsePacket(b []byte) (*Packet, int, error) {
+	if len(b) < 6 {
+		return nil, 0, fmt.Errorf("ccsds: header requires at least 6 bytes, got %d", len(b))
+	}
+
+	pkt := &Packet{
+		Version:       (b[0] & 0xE0) >> 5,
+		Type:          (b[0] & 0x10) >> 4,
+		SecondaryHdr:  (b[0] & 0x08) != 0,
+		APID:          uint16(b[0]&0x07)<<8 | uint16(b[1]),
+		SequenceFlags: (b[2] & 0xC0) >> 6,
+		SequenceCount: binary.BigEndian.Uint16([]byte{b[2] & 0x3F, b[3]}),
+		DataLen:       binary.BigEndian.Uint16(b[4:6]) + 1,
+	}
+
+	total := 6 + int(pkt.DataLen)
+	if len(b) < total {
+		return nil, 0, fmt.Errorf("ccsds: data requires %d bytes, got %d", pkt.DataLen, len(b)-6)
+	}
+
+	pkt.Data = b[6:total]
+
+	return pkt, total, nil
+}
+
+// ReadPacketInto reads and decodes a single packet into dst, using buf as
+// storage for dst.Data when cap(buf) is large enough to hold it; otherwise
+// it allocates a new slice. It returns the number of data bytes read.
+func (d *Decoder) ReadPacketInto(dst *Packet, buf []byte) (int, error) {
+	var header [6]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		return 0, err
+	}
+
+	dst.Version = (header[0] & 0xE0) >> 5
+	dst.Type = (header[0] & 0x10) >> 4
+	dst.SecondaryHdr = (header[0] & 0x08) != 0
+	dst.APID = uint16(header[0]&0x07)<<8 | uint16(header[1])
+	dst.SequenceFlags = (header[2] & 0xC0) >> 6
+	dst.SequenceCount = binary.BigEndian.Uint16([]byte{header[2] & 0x3F, header[3]})
+	dst.DataLen = binary.BigEndian.Uint16(header[4:6]) + 1
+
+	n := int(dst.DataLen)
+	if cap(buf) >= n {
+		dst.Data = buf[:n]
+	} else {
+		dst.Data = make([]byte, n)
+	}
+
+	if _, err := io.ReadFull(d.r, dst.Data); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// DecoderPool recycles Packets and their Data buffers across many
+// ReadPacket calls, so a packet needs no new Packet or Data slice of its
+// own at high packet rates. Reading each packet's 6-byte primary header
+// still allocates. A DecoderPool is safe for concurrent use.
+type DecoderPool struct {
+	pool sync.Pool
+}
+
+// NewDecoderPool creates an empty DecoderPool.
+func NewDecoderPool() *DecoderPool {
+	return &DecoderPool{
+		pool: sync.Pool{
+			New: func() interface{} {
+				return &Packet{}
+			},
+		},
+	}
+}
+
+// Get returns a Packet from the pool, allocating a new one if the pool is
+// empty. Its Data slice is reset to length zero but keeps its underlying
+// array, so a later ReadPacket call can reuse that storage.
+func (p *DecoderPool) Get() *Packet {
+	pkt := p.pool.Get().(*Packet)
+	pkt.Data = pkt.Data[:0]
+	return pkt
+}
+
+// Put returns pkt to the pool so a later Get call can reuse its storage.
+// The caller must not use pkt again afterward.
+func (p *DecoderPool) Put(pkt *Packet) {
+	p.pool.Put(pkt)
+}
+
+// ReadPacket reads and decodes a single packet from d into a Packet drawn
+// from the pool, reusing its Data buffer when large enough. Call Put once
+// the returned Packet is no longer needed to recycle its storage.
+func (p *DecoderPool) ReadPacket(d *Decoder) (*Packet, error) {
+	pkt := p.Get()
+
+	if _, err := d.ReadPacketInto(pkt, pkt.Data[:cap(pkt.Data)]); err != nil {
+		p.Put(pkt)
+		return nil, err
+	}
+
+	return pkt, nil
+}
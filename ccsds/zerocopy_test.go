@@ -0,0 +1,122 @@
+package ccsds
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePacket_AliasesInput(t *testing.T) {
+	b := encodeTestPacket(t, &Packet{APID: 5, Data: []byte{0xDE, 0xAD, 0xBE}})
+
+	pkt, n, err := ParsePacket(b)
+	assert.NoError(t, err)
+	assert.Equal(t, len(b), n)
+	assert.Equal(t, uint16(5), pkt.APID)
+	assert.Equal(t, []byte{0xDE, 0xAD, 0xBE}, pkt.Data)
+
+	// Data aliases b: mutating b is visible through pkt.Data.
+	b[6] = 0xFF
+	assert.Equal(t, byte(0xFF), pkt.Data[0])
+}
+
+func TestParsePacket_ShortBuffer(t *testing.T) {
+	_, _, err := ParsePacket([]byte{0x00, 0x01})
+	assert.Error(t, err)
+}
+
+func TestDecoder_ReadPacketInto_ReusesBuffer(t *testing.T) {
+	b := encodeTestPacket(t, &Packet{APID: 7, Data: []byte{0x01, 0x02, 0x03}})
+
+	d := NewDecoder(bytes.NewReader(b))
+
+	var pkt Packet
+	buf := make([]byte, 3, 16)
+	n, err := d.ReadPacketInto(&pkt, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, uint16(7), pkt.APID)
+	assert.Equal(t, []byte{0x01, 0x02, 0x03}, pkt.Data)
+	assert.Same(t, &buf[0], &pkt.Data[0])
+}
+
+func TestDecoderPool_ReadPacket(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(encodeTestPacket(t, &Packet{APID: 1, Data: []byte{0x01}}))
+	stream.Write(encodeTestPacket(t, &Packet{APID: 2, Data: []byte{0x02, 0x03}}))
+
+	d := NewDecoder(&stream)
+	pool := NewDecoderPool()
+
+	pkt1, err := pool.ReadPacket(d)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(1), pkt1.APID)
+	assert.Equal(t, []byte{0x01}, pkt1.Data)
+
+	pool.Put(pkt1)
+
+	pkt2, err := pool.ReadPacket(d)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(2), pkt2.APID)
+	assert.Equal(t, []byte{0x02, 0x03}, pkt2.Data)
+}
+
+func BenchmarkDecoder_ReadPacket(b *testing.B) {
+	pkt := &Packet{APID: 1, Data: make([]byte, 256)}
+	encoded, err := pkt.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d := NewDecoder(bytes.NewReader(encoded))
+		if _, err := d.ReadPacket(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecoderPool_ReadPacket(b *testing.B) {
+	pkt := &Packet{APID: 1, Data: make([]byte, 256)}
+	encoded, err := pkt.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	pool := NewDecoderPool()
+	r := bytes.NewReader(encoded)
+	d := NewDecoder(r)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.Reset(encoded)
+		got, err := pool.ReadPacket(d)
+		if err != nil {
+			b.Fatal(err)
+		}
+		pool.Put(got)
+	}
+}
+
+func BenchmarkParsePacket(b *testing.B) {
+	pkt := &Packet{APID: 1, Data: make([]byte, 256)}
+	encoded, err := pkt.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ParsePacket(encoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}